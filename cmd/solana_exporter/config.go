@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc"
+)
+
+// ExporterConfig holds all the configuration needed to run the exporter, populated from CLI flags.
+type ExporterConfig struct {
+	HttpTimeout               time.Duration
+	RpcUrl                    string
+	WsUrl                     string
+	ListenAddress             string
+	NodeKeys                  []string
+	BalanceAddresses          []string
+	ComprehensiveSlotTracking bool
+	MonitorBlockSizes         bool
+	Commitments               []rpc.Commitment
+	RpcFailoverUrls           []string
+	RpcFailoverPolicy         rpc.FailoverPolicy
+	RpcDisagreementTolerance  int64
+	ReadinessMaxStaleness     time.Duration
+	AccountWatchConfigPath    string
+	AccountWatchPollPeriod    time.Duration
+}
+
+func splitAndTrim(s string) []string {
+	if s == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// NewExporterConfigFromCLI parses CLI flags into an ExporterConfig.
+func NewExporterConfigFromCLI() *ExporterConfig {
+	var (
+		httpTimeout = flag.Duration(
+			"http-timeout", 60*time.Second, "HTTP timeout to use, in seconds.",
+		)
+		rpcUrl = flag.String(
+			"rpc-url", "http://localhost:8899", "Solana RPC URL (including protocol and port), "+
+				"e.g., 'http://localhost:8899'.",
+		)
+		wsUrl = flag.String(
+			"ws-url", "", "Solana WebSocket URL (including protocol and port), e.g., 'ws://localhost:8900'. "+
+				"When set, the exporter also subscribes to slot/root/vote notifications, in addition to polling.",
+		)
+		listenAddress = flag.String(
+			"listen-address", ":8080", "Address to listen on for web interface and telemetry.",
+		)
+		nodeKeys = flag.String(
+			"node-keys", "", "Comma-separated list of validator nodekeys to monitor.",
+		)
+		balanceAddresses = flag.String(
+			"balance-addresses", "", "Comma-separated list of addresses to monitor the SOL balances of, "+
+				"in addition to the provided node keys.",
+		)
+		comprehensiveSlotTracking = flag.Bool(
+			"comprehensive-slot-tracking", false, "Set this flag to track detailed, per-slot metrics "+
+				"rather than the default coarser metrics.",
+		)
+		monitorBlockSizes = flag.Bool(
+			"monitor-block-sizes", false, "Set this flag to enable monitoring of block sizes (transaction "+
+				"counts), in addition to the default metrics.",
+		)
+		commitments = flag.String(
+			"commitments", "confirmed", "Comma-separated list of commitment levels to collect metrics at "+
+				"(any of 'processed', 'confirmed', 'finalized').",
+		)
+		rpcFailoverUrls = flag.String(
+			"rpc-failover-urls", "", "Comma-separated list of backup RPC URLs to use in addition to "+
+				"-rpc-url, e.g., for a local validator plus one or more public endpoints.",
+		)
+		rpcFailover = flag.String(
+			"rpc-failover", "sequential", "Policy for spreading requests across -rpc-url and "+
+				"-rpc-failover-urls: one of 'sequential', 'round-robin', or 'shadow'.",
+		)
+		rpcDisagreementTolerance = flag.Int64(
+			"rpc-disagreement-tolerance", 0, "Maximum slot difference endpoints may disagree on, in "+
+				"'shadow' -rpc-failover mode, before it's reported as a disagreement.",
+		)
+		readinessMaxStaleness = flag.Duration(
+			"readiness-max-staleness", 60*time.Second, "How long /readyz considers the exporter ready "+
+				"after the most recent successful RPC round-trip, before reporting not-ready.",
+		)
+		accountWatchConfig = flag.String(
+			"account-watch-config", "", "Path to a YAML file listing program accounts to watch "+
+				"(see pkg/accountwatch for the schema). Disabled if unset.",
+		)
+		accountWatchPollPeriod = flag.Duration(
+			"account-watch-poll-period", 10*time.Second, "How often to poll accounts listed in "+
+				"-account-watch-config.",
+		)
+	)
+	flag.Parse()
+
+	var commitmentLevels []rpc.Commitment
+	for _, commitment := range splitAndTrim(*commitments) {
+		commitmentLevels = append(commitmentLevels, rpc.Commitment(commitment))
+	}
+
+	return &ExporterConfig{
+		HttpTimeout:               *httpTimeout,
+		RpcUrl:                    *rpcUrl,
+		WsUrl:                     *wsUrl,
+		ListenAddress:             *listenAddress,
+		NodeKeys:                  splitAndTrim(*nodeKeys),
+		BalanceAddresses:          splitAndTrim(*balanceAddresses),
+		ComprehensiveSlotTracking: *comprehensiveSlotTracking,
+		MonitorBlockSizes:         *monitorBlockSizes,
+		Commitments:               commitmentLevels,
+		RpcFailoverUrls:           splitAndTrim(*rpcFailoverUrls),
+		RpcFailoverPolicy:         rpc.FailoverPolicy(*rpcFailover),
+		RpcDisagreementTolerance:  *rpcDisagreementTolerance,
+		ReadinessMaxStaleness:     *readinessMaxStaleness,
+		AccountWatchConfigPath:    *accountWatchConfig,
+		AccountWatchPollPeriod:    *accountWatchPollPeriod,
+	}
+}
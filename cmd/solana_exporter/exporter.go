@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/asymmetric-research/solana_exporter/pkg/accountwatch"
 	"github.com/asymmetric-research/solana_exporter/pkg/rpc"
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc/ws"
 	"github.com/asymmetric-research/solana_exporter/pkg/slog"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -22,6 +25,8 @@ const (
 	AddressLabel    = "address"
 	EpochLabel      = "epoch"
 	IdentityLabel   = "identity"
+	UrlLabel        = "url"
+	CommitmentLabel = "commitment"
 
 	StatusSkipped = "skipped"
 	StatusValid   = "valid"
@@ -32,12 +37,15 @@ const (
 
 type SolanaCollector struct {
 	rpcClient rpc.Provider
+	wsClient  *ws.Client
 	logger    *zap.SugaredLogger
 
 	// config:
 	slotPace         time.Duration
 	balanceAddresses []string
 	identity         string
+	commitments      []rpc.Commitment
+	readiness        *ReadinessTracker
 
 	/// descriptors:
 	ValidatorActive         *GaugeDesc
@@ -51,44 +59,62 @@ type SolanaCollector struct {
 	NodeNumSlotsBehind      *GaugeDesc
 	NodeMinimumLedgerSlot   *GaugeDesc
 	NodeFirstAvailableBlock *GaugeDesc
+	WsConnected             *GaugeDesc
+	WsReconnectsTotal       *GaugeDesc
 }
 
 func NewSolanaCollector(
-	provider rpc.Provider, slotPace time.Duration, balanceAddresses, nodekeys, votekeys []string, identity string,
+	provider rpc.Provider, wsClient *ws.Client, slotPace time.Duration, balanceAddresses, nodekeys, votekeys []string,
+	identity string, commitments []rpc.Commitment, readiness *ReadinessTracker,
 ) *SolanaCollector {
 	collector := &SolanaCollector{
 		rpcClient:        provider,
+		wsClient:         wsClient,
 		logger:           slog.Get(),
 		slotPace:         slotPace,
 		balanceAddresses: CombineUnique(balanceAddresses, nodekeys, votekeys),
 		identity:         identity,
+		commitments:      commitments,
+		readiness:        readiness,
 		ValidatorActive: NewGaugeDesc(
 			"solana_validator_active",
 			fmt.Sprintf(
-				"Total number of active validators, grouped by %s ('%s' or '%s')",
-				StateLabel, StateCurrent, StateDelinquent,
+				"Total number of active validators, grouped by %s ('%s' or '%s') and %s",
+				StateLabel, StateCurrent, StateDelinquent, CommitmentLabel,
 			),
-			StateLabel,
+			StateLabel, CommitmentLabel,
 		),
 		ValidatorActiveStake: NewGaugeDesc(
 			"solana_validator_active_stake",
-			fmt.Sprintf("Active stake per validator (represented by %s and %s)", VotekeyLabel, NodekeyLabel),
-			VotekeyLabel, NodekeyLabel,
+			fmt.Sprintf(
+				"Active stake per validator (represented by %s and %s), by %s",
+				VotekeyLabel, NodekeyLabel, CommitmentLabel,
+			),
+			VotekeyLabel, NodekeyLabel, CommitmentLabel,
 		),
 		ValidatorLastVote: NewGaugeDesc(
 			"solana_validator_last_vote",
-			fmt.Sprintf("Last voted-on slot per validator (represented by %s and %s)", VotekeyLabel, NodekeyLabel),
-			VotekeyLabel, NodekeyLabel,
+			fmt.Sprintf(
+				"Last voted-on slot per validator (represented by %s and %s), by %s",
+				VotekeyLabel, NodekeyLabel, CommitmentLabel,
+			),
+			VotekeyLabel, NodekeyLabel, CommitmentLabel,
 		),
 		ValidatorRootSlot: NewGaugeDesc(
 			"solana_validator_root_slot",
-			fmt.Sprintf("Root slot per validator (represented by %s and %s)", VotekeyLabel, NodekeyLabel),
-			VotekeyLabel, NodekeyLabel,
+			fmt.Sprintf(
+				"Root slot per validator (represented by %s and %s), by %s",
+				VotekeyLabel, NodekeyLabel, CommitmentLabel,
+			),
+			VotekeyLabel, NodekeyLabel, CommitmentLabel,
 		),
 		ValidatorDelinquent: NewGaugeDesc(
 			"solana_validator_delinquent",
-			fmt.Sprintf("Whether a validator (represented by %s and %s) is delinquent", VotekeyLabel, NodekeyLabel),
-			VotekeyLabel, NodekeyLabel,
+			fmt.Sprintf(
+				"Whether a validator (represented by %s and %s) is delinquent, by %s",
+				VotekeyLabel, NodekeyLabel, CommitmentLabel,
+			),
+			VotekeyLabel, NodekeyLabel, CommitmentLabel,
 		),
 		AccountBalances: NewGaugeDesc(
 			"solana_account_balance",
@@ -126,6 +152,16 @@ func NewSolanaCollector(
 			),
 			IdentityLabel,
 		),
+		WsConnected: NewGaugeDesc(
+			"solana_ws_connected",
+			fmt.Sprintf("Whether the WebSocket connection (%s) is currently established", UrlLabel),
+			UrlLabel,
+		),
+		WsReconnectsTotal: NewGaugeDesc(
+			"solana_ws_reconnects_total",
+			fmt.Sprintf("Total number of times the WebSocket connection (%s) has been (re-)established", UrlLabel),
+			UrlLabel,
+		),
 	}
 	return collector
 }
@@ -142,36 +178,68 @@ func (c *SolanaCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.NodeNumSlotsBehind.Desc
 	ch <- c.NodeMinimumLedgerSlot.Desc
 	ch <- c.NodeFirstAvailableBlock.Desc
+	ch <- c.WsConnected.Desc
+	ch <- c.WsReconnectsTotal.Desc
 }
 
-func (c *SolanaCollector) collectVoteAccounts(ctx context.Context, ch chan<- prometheus.Metric) {
-	voteAccounts, err := c.rpcClient.GetVoteAccounts(ctx, rpc.CommitmentConfirmed, nil)
+func (c *SolanaCollector) collectVoteAccountsAtCommitment(
+	ctx context.Context, ch chan<- prometheus.Metric, commitment rpc.Commitment,
+) error {
+	voteAccounts, err := c.rpcClient.GetVoteAccounts(ctx, commitment, nil)
 	if err != nil {
-		c.logger.Errorf("failed to get vote accounts: %v", err)
+		c.logger.Errorf("failed to get vote accounts at commitment %s: %v", commitment, err)
 		ch <- c.ValidatorActive.NewInvalidMetric(err)
 		ch <- c.ValidatorActiveStake.NewInvalidMetric(err)
 		ch <- c.ValidatorLastVote.NewInvalidMetric(err)
 		ch <- c.ValidatorRootSlot.NewInvalidMetric(err)
 		ch <- c.ValidatorDelinquent.NewInvalidMetric(err)
-		return
+		return err
 	}
 
-	ch <- c.ValidatorActive.MustNewConstMetric(float64(len(voteAccounts.Delinquent)), StateDelinquent)
-	ch <- c.ValidatorActive.MustNewConstMetric(float64(len(voteAccounts.Current)), StateCurrent)
+	ch <- c.ValidatorActive.MustNewConstMetric(float64(len(voteAccounts.Delinquent)), StateDelinquent, string(commitment))
+	ch <- c.ValidatorActive.MustNewConstMetric(float64(len(voteAccounts.Current)), StateCurrent, string(commitment))
+
+	wsHealthy := c.wsClient != nil && c.wsClient.Connected()
 
 	for _, account := range append(voteAccounts.Current, voteAccounts.Delinquent...) {
 		accounts := []string{account.VotePubkey, account.NodePubkey}
-		ch <- c.ValidatorActiveStake.MustNewConstMetric(float64(account.ActivatedStake), accounts...)
-		ch <- c.ValidatorLastVote.MustNewConstMetric(float64(account.LastVote), accounts...)
-		ch <- c.ValidatorRootSlot.MustNewConstMetric(float64(account.RootSlot), accounts...)
+		lastVote := account.LastVote
+		if wsHealthy {
+			if cached, ok := c.wsClient.Cache().LastVote(account.VotePubkey); ok {
+				lastVote = cached
+			}
+		}
+		ch <- c.ValidatorActiveStake.MustNewConstMetric(float64(account.ActivatedStake), append(accounts, string(commitment))...)
+		ch <- c.ValidatorLastVote.MustNewConstMetric(float64(lastVote), append(accounts, string(commitment))...)
+		ch <- c.ValidatorRootSlot.MustNewConstMetric(float64(account.RootSlot), append(accounts, string(commitment))...)
 	}
 
 	for _, account := range voteAccounts.Current {
-		ch <- c.ValidatorDelinquent.MustNewConstMetric(0, account.VotePubkey, account.NodePubkey)
+		ch <- c.ValidatorDelinquent.MustNewConstMetric(0, account.VotePubkey, account.NodePubkey, string(commitment))
 	}
 	for _, account := range voteAccounts.Delinquent {
-		ch <- c.ValidatorDelinquent.MustNewConstMetric(1, account.VotePubkey, account.NodePubkey)
+		ch <- c.ValidatorDelinquent.MustNewConstMetric(1, account.VotePubkey, account.NodePubkey, string(commitment))
 	}
+	return nil
+}
+
+// collectVoteAccounts fetches vote accounts once per configured commitment level, in parallel,
+// so that dashboards can graph confirmed-vs-finalized skew.
+func (c *SolanaCollector) collectVoteAccounts(ctx context.Context, ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	for _, commitment := range c.commitments {
+		commitment := commitment
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// errors are already surfaced as invalid metrics per-commitment; nothing more to do
+			// with them here. Crucially, this uses ctx directly rather than an errgroup-derived
+			// context, so a failure at one commitment level can never cancel the in-flight
+			// request for another.
+			_ = c.collectVoteAccountsAtCommitment(ctx, ch, commitment)
+		}()
+	}
+	wg.Wait()
 }
 
 func (c *SolanaCollector) collectVersion(ctx context.Context, ch chan<- prometheus.Metric) {
@@ -183,6 +251,9 @@ func (c *SolanaCollector) collectVersion(ctx context.Context, ch chan<- promethe
 		return
 	}
 
+	if c.readiness != nil {
+		c.readiness.MarkSuccess()
+	}
 	ch <- c.NodeVersion.MustNewConstMetric(1, version)
 }
 func (c *SolanaCollector) collectMinimumLedgerSlot(ctx context.Context, ch chan<- prometheus.Metric) {
@@ -254,12 +325,27 @@ func (c *SolanaCollector) collectHealth(ctx context.Context, ch chan<- prometheu
 		}
 	}
 
+	if c.readiness != nil {
+		c.readiness.MarkSuccess()
+	}
 	ch <- c.NodeIsHealthy.MustNewConstMetric(float64(isHealthy), c.identity)
 	ch <- c.NodeNumSlotsBehind.MustNewConstMetric(float64(numSlotsBehind), c.identity)
 
 	return
 }
 
+func (c *SolanaCollector) collectWs(ch chan<- prometheus.Metric) {
+	if c.wsClient == nil {
+		return
+	}
+	connected := 0.
+	if c.wsClient.Connected() {
+		connected = 1
+	}
+	ch <- c.WsConnected.MustNewConstMetric(connected, c.wsClient.Url())
+	ch <- c.WsReconnectsTotal.MustNewConstMetric(float64(c.wsClient.Reconnects()), c.wsClient.Url())
+}
+
 func (c *SolanaCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -270,6 +356,7 @@ func (c *SolanaCollector) Collect(ch chan<- prometheus.Metric) {
 	c.collectHealth(ctx, ch)
 	c.collectMinimumLedgerSlot(ctx, ch)
 	c.collectFirstAvailableBlock(ctx, ch)
+	c.collectWs(ch)
 }
 
 func main() {
@@ -284,7 +371,21 @@ func main() {
 		)
 	}
 
-	client := rpc.NewRPCClient(config.RpcUrl, config.HttpTimeout)
+	var baseProvider rpc.Provider
+	if len(config.RpcFailoverUrls) > 0 {
+		multiClient := rpc.NewMultiClient(
+			append([]string{config.RpcUrl}, config.RpcFailoverUrls...),
+			config.HttpTimeout, config.RpcFailoverPolicy, config.RpcDisagreementTolerance,
+		)
+		prometheus.MustRegister(multiClient.Up, multiClient.Disagreement)
+		baseProvider = multiClient
+	} else {
+		baseProvider = rpc.NewRPCClient(config.RpcUrl, config.HttpTimeout)
+	}
+
+	instrumentedClient := rpc.NewInstrumentedProvider(baseProvider)
+	prometheus.MustRegister(instrumentedClient.RequestDuration, instrumentedClient.Errors)
+	var client rpc.Provider = instrumentedClient
 	votekeys, err := GetAssociatedVoteAccounts(ctx, client, rpc.CommitmentFinalized, config.NodeKeys)
 	if err != nil {
 		logger.Fatalf("Failed to get associated vote accounts for %v: %v", config.NodeKeys, err)
@@ -293,18 +394,67 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Failed to get identity: %v", err)
 	}
+
+	var wsClient *ws.Client
+	if config.WsUrl != "" {
+		wsClient = ws.NewClient(config.WsUrl)
+	}
+
+	readiness := NewReadinessTracker(config.ReadinessMaxStaleness)
 	collector := NewSolanaCollector(
-		client, slotPacerSchedule, config.BalanceAddresses, config.NodeKeys, votekeys, identity,
+		client, wsClient, slotPacerSchedule, config.BalanceAddresses, config.NodeKeys, votekeys, identity,
+		config.Commitments, readiness,
 	)
 	slotWatcher := NewSlotWatcher(
-		client, config.NodeKeys, votekeys, identity, config.ComprehensiveSlotTracking, config.MonitorBlockSizes,
+		client, wsClient, config.NodeKeys, votekeys, identity, config.ComprehensiveSlotTracking,
+		config.MonitorBlockSizes, config.Commitments, readiness,
 	)
+	prometheus.MustRegister(slotWatcher.CurrentSlot, slotWatcher.NodeRootSlot)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	if wsClient != nil {
+		go wsClient.Run(ctx)
+	}
+	go readiness.Run(ctx, client)
 	go slotWatcher.WatchSlots(ctx, collector.slotPace)
 
+	if config.AccountWatchConfigPath != "" {
+		accountWatchCommitment := rpc.CommitmentConfirmed
+		if len(config.Commitments) > 0 {
+			accountWatchCommitment = config.Commitments[0]
+		}
+		accountWatchConfig, err := accountwatch.LoadConfig(config.AccountWatchConfigPath)
+		if err != nil {
+			logger.Fatalf("Failed to load account-watch config: %v", err)
+		}
+		accountWatcher, err := accountwatch.NewWatcher(
+			client, accountWatchCommitment, config.AccountWatchPollPeriod, accountWatchConfig,
+		)
+		if err != nil {
+			logger.Fatalf("Failed to build account watcher: %v", err)
+		}
+		prometheus.MustRegister(accountWatcher.AccountField, accountWatcher.AccountLastSlot)
+		go accountWatcher.Run(ctx)
+	}
+
 	prometheus.MustRegister(collector)
 	http.Handle("/metrics", promhttp.Handler())
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	http.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		wsConnected := func() bool {
+			if wsClient == nil {
+				return true
+			}
+			return wsClient.Connected()
+		}
+		if !readiness.Ready(wsConnected) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
 
 	logger.Infof("listening on %s", config.ListenAddress)
 	logger.Fatal(http.ListenAndServe(config.ListenAddress, nil))
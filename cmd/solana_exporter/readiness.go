@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc"
+)
+
+// readinessPollFraction and readinessPollIntervalFloor derive ReadinessTracker.Run's poll
+// interval from its configured maxStaleness: polling at a fraction of the staleness budget
+// (rather than a fixed interval) keeps /readyz from flapping not-ready on a schedule whenever
+// --readiness-max-staleness is tightened below a hardcoded poll period.
+const (
+	readinessPollFraction      = 3
+	readinessPollIntervalFloor = 1 * time.Second
+)
+
+// ReadinessTracker records the timestamp of the most recent successful RPC round-trip
+// (GetHealth / GetVersion / a slot-watcher tick) so that /readyz can report whether the
+// exporter's view of the cluster is still fresh.
+type ReadinessTracker struct {
+	mu           sync.Mutex
+	lastSuccess  time.Time
+	maxStaleness time.Duration
+}
+
+// NewReadinessTracker creates a tracker that considers the exporter stale once maxStaleness
+// has elapsed since the last successful RPC round-trip.
+func NewReadinessTracker(maxStaleness time.Duration) *ReadinessTracker {
+	return &ReadinessTracker{maxStaleness: maxStaleness}
+}
+
+// MarkSuccess records that an RPC round-trip just succeeded.
+func (r *ReadinessTracker) MarkSuccess() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastSuccess = time.Now()
+}
+
+// Ready reports whether the last successful RPC round-trip happened within the configured
+// staleness window, and, if wsConnected is non-nil, whether the WebSocket is also connected.
+func (r *ReadinessTracker) Ready(wsConnected func() bool) bool {
+	r.mu.Lock()
+	lastSuccess := r.lastSuccess
+	r.mu.Unlock()
+
+	if lastSuccess.IsZero() || time.Since(lastSuccess) > r.maxStaleness {
+		return false
+	}
+	if wsConnected != nil && !wsConnected() {
+		return false
+	}
+	return true
+}
+
+// pollInterval is a fraction of maxStaleness, floored at readinessPollIntervalFloor, so that
+// polling keeps up with maxStaleness down to that floor; a maxStaleness at or below the floor
+// is polled no more often than once per floor interval.
+func (r *ReadinessTracker) pollInterval() time.Duration {
+	interval := r.maxStaleness / readinessPollFraction
+	if interval < readinessPollIntervalFloor {
+		return readinessPollIntervalFloor
+	}
+	return interval
+}
+
+// Run probes provider on its own ticker, independent of /metrics scrapes, so /readyz reflects
+// the exporter's own health rather than however often (or rarely) something scrapes /metrics.
+// It blocks until ctx is cancelled.
+func (r *ReadinessTracker) Run(ctx context.Context, provider rpc.Provider) {
+	ticker := time.NewTicker(r.pollInterval())
+	defer ticker.Stop()
+
+	r.poll(ctx, provider)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.poll(ctx, provider)
+		}
+	}
+}
+
+func (r *ReadinessTracker) poll(ctx context.Context, provider rpc.Provider) {
+	if _, err := provider.GetHealth(ctx); err != nil {
+		// a structured "node unhealthy" RPC error still means the round-trip itself succeeded.
+		var rpcErr *rpc.RPCError
+		if !errors.As(err, &rpcErr) {
+			return
+		}
+	}
+	r.MarkSuccess()
+}
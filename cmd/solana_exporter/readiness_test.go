@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc"
+)
+
+// fakeHealthProvider is an rpc.Provider whose GetHealth is the only method exercised by
+// ReadinessTracker; every other method panics if called.
+type fakeHealthProvider struct {
+	err error
+}
+
+func (f *fakeHealthProvider) GetHealth(ctx context.Context) (string, error) { return "ok", f.err }
+func (f *fakeHealthProvider) GetVersion(ctx context.Context) (string, error) { panic("not used") }
+func (f *fakeHealthProvider) GetIdentity(ctx context.Context) (string, error) { panic("not used") }
+func (f *fakeHealthProvider) GetMinimumLedgerSlot(ctx context.Context) (*int64, error) {
+	panic("not used")
+}
+func (f *fakeHealthProvider) GetFirstAvailableBlock(ctx context.Context) (*int64, error) {
+	panic("not used")
+}
+func (f *fakeHealthProvider) GetVoteAccounts(
+	ctx context.Context, commitment rpc.Commitment, votePubkey *string,
+) (*rpc.VoteAccounts, error) {
+	panic("not used")
+}
+func (f *fakeHealthProvider) GetMultipleAccounts(
+	ctx context.Context, commitment rpc.Commitment, pubkeys []string,
+) ([]rpc.AccountInfo, error) {
+	panic("not used")
+}
+func (f *fakeHealthProvider) GetSlot(ctx context.Context, commitment rpc.Commitment) (int64, error) {
+	panic("not used")
+}
+
+func TestReadinessTracker_ReadyReflectsStaleness(t *testing.T) {
+	r := NewReadinessTracker(30 * time.Millisecond)
+
+	if r.Ready(nil) {
+		t.Fatal("expected a tracker with no successes yet to be not-ready")
+	}
+
+	r.MarkSuccess()
+	if !r.Ready(nil) {
+		t.Fatal("expected the tracker to be ready immediately after a success")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if r.Ready(nil) {
+		t.Fatal("expected the tracker to go stale once maxStaleness has elapsed")
+	}
+}
+
+func TestReadinessTracker_ReadyRequiresWsConnected(t *testing.T) {
+	r := NewReadinessTracker(time.Minute)
+	r.MarkSuccess()
+
+	if r.Ready(func() bool { return false }) {
+		t.Fatal("expected Ready to report false when the WS connectivity check fails")
+	}
+	if !r.Ready(func() bool { return true }) {
+		t.Fatal("expected Ready to report true when the WS connectivity check passes")
+	}
+}
+
+func TestReadinessTracker_PollIntervalDerivesFromMaxStaleness(t *testing.T) {
+	cases := []struct {
+		maxStaleness time.Duration
+		want         time.Duration
+	}{
+		{maxStaleness: time.Second, want: readinessPollIntervalFloor},
+		{maxStaleness: 30 * time.Second, want: 10 * time.Second},
+	}
+	for _, tc := range cases {
+		r := NewReadinessTracker(tc.maxStaleness)
+		if got := r.pollInterval(); got != tc.want {
+			t.Errorf("pollInterval() for maxStaleness=%s = %s, want %s", tc.maxStaleness, got, tc.want)
+		}
+	}
+}
+
+func TestReadinessTracker_PollMarksSuccessOnHealthyProvider(t *testing.T) {
+	r := NewReadinessTracker(time.Minute)
+	r.poll(context.Background(), &fakeHealthProvider{})
+
+	if !r.Ready(nil) {
+		t.Fatal("expected poll to mark success when GetHealth succeeds")
+	}
+}
+
+func TestReadinessTracker_PollMarksSuccessOnRoundTripThatSucceedsButReportsUnhealthy(t *testing.T) {
+	r := NewReadinessTracker(time.Minute)
+	r.poll(context.Background(), &fakeHealthProvider{err: &rpc.RPCError{Code: -1, Message: "node unhealthy"}})
+
+	if !r.Ready(nil) {
+		t.Fatal("expected a structured RPC error to still count as a successful round-trip")
+	}
+}
+
+func TestReadinessTracker_PollDoesNotMarkSuccessOnTransportError(t *testing.T) {
+	r := NewReadinessTracker(time.Minute)
+	r.poll(context.Background(), &fakeHealthProvider{err: fmt.Errorf("dial tcp: connection refused")})
+
+	if r.Ready(nil) {
+		t.Fatal("expected a transport-level error to not count as a successful round-trip")
+	}
+}
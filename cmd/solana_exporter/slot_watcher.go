@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc"
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc/ws"
+	"github.com/asymmetric-research/solana_exporter/pkg/slog"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// SlotWatcher tracks the current slot per configured commitment level by polling GetSlot on a
+// fixed pace. When a WS client is configured and connected it additionally publishes the
+// slotSubscribe/rootSubscribe values pushed through ws.Client.Notify between polls.
+type SlotWatcher struct {
+	rpcClient                 rpc.Provider
+	wsClient                  *ws.Client
+	logger                    *zap.SugaredLogger
+	nodekeys                  []string
+	votekeys                  []string
+	identity                  string
+	comprehensiveSlotTracking bool
+	monitorBlockSizes         bool
+	commitments               []rpc.Commitment
+	readiness                 *ReadinessTracker
+
+	CurrentSlot  *prometheus.GaugeVec
+	NodeRootSlot prometheus.Gauge
+}
+
+// NewSlotWatcher builds a SlotWatcher. wsClient and readiness may both be nil.
+func NewSlotWatcher(
+	provider rpc.Provider, wsClient *ws.Client, nodekeys, votekeys []string, identity string,
+	comprehensiveSlotTracking, monitorBlockSizes bool, commitments []rpc.Commitment, readiness *ReadinessTracker,
+) *SlotWatcher {
+	return &SlotWatcher{
+		rpcClient:                 provider,
+		wsClient:                  wsClient,
+		logger:                    slog.Get(),
+		nodekeys:                  nodekeys,
+		votekeys:                  votekeys,
+		identity:                  identity,
+		comprehensiveSlotTracking: comprehensiveSlotTracking,
+		monitorBlockSizes:         monitorBlockSizes,
+		commitments:               commitments,
+		readiness:                 readiness,
+		CurrentSlot: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "solana_node_slot_height",
+				Help: fmt.Sprintf("The current slot that the node is processing, by %s", CommitmentLabel),
+			},
+			[]string{CommitmentLabel},
+		),
+		NodeRootSlot: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "solana_node_root_slot",
+				Help: "The current root slot of the node, pushed via the WS rootSubscribe notification.",
+			},
+		),
+	}
+}
+
+// WatchSlots publishes the current slot until ctx is cancelled. It polls on pace whether or not
+// a WS client is connected; while one is connected it also reacts to its push notifications.
+func (w *SlotWatcher) WatchSlots(ctx context.Context, pace time.Duration) {
+	ticker := time.NewTicker(pace)
+	defer ticker.Stop()
+
+	w.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		case <-w.notifyChan():
+			w.tickFromCache()
+		}
+	}
+}
+
+// notifyChan returns the WS client's notification channel if it's configured and connected, or
+// nil otherwise. A nil channel blocks forever in a select, so this cleanly disables the push
+// path when there's no healthy WS connection to drive it.
+func (w *SlotWatcher) notifyChan() <-chan struct{} {
+	if w.wsClient == nil || !w.wsClient.Connected() {
+		return nil
+	}
+	return w.wsClient.Notify
+}
+
+// tickFromCache publishes the push-delivered WS slot and root slot. The WS subscription reflects
+// the node's own (processed) view, so the slot is published under CommitmentProcessed.
+func (w *SlotWatcher) tickFromCache() {
+	cache := w.wsClient.Cache()
+	w.CurrentSlot.WithLabelValues(string(rpc.CommitmentProcessed)).Set(float64(cache.CurrentSlot()))
+	w.NodeRootSlot.Set(float64(cache.RootSlot()))
+	if w.readiness != nil {
+		w.readiness.MarkSuccess()
+	}
+}
+
+// tick polls GetSlot once per configured commitment level and records the result, marking the
+// readiness tracker on success just like a successful /metrics collection would.
+func (w *SlotWatcher) tick(ctx context.Context) {
+	for _, commitment := range w.commitments {
+		slot, err := w.rpcClient.GetSlot(ctx, commitment)
+		if err != nil {
+			w.logger.Errorf("failed to get slot at commitment %s: %v", commitment, err)
+			continue
+		}
+		w.CurrentSlot.WithLabelValues(string(commitment)).Set(float64(slot))
+		if w.readiness != nil {
+			w.readiness.MarkSuccess()
+		}
+	}
+}
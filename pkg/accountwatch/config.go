@@ -0,0 +1,45 @@
+package accountwatch
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AccountConfig describes a single on-chain account to watch: its human-readable name, its
+// pubkey, and the decoder used to turn its raw data into Prometheus fields.
+type AccountConfig struct {
+	Name    string `yaml:"name"`
+	Pubkey  string `yaml:"pubkey"`
+	Decoder string `yaml:"decoder"`
+}
+
+// Config is the top-level shape of the --account-watch-config YAML file.
+type Config struct {
+	Accounts []AccountConfig `yaml:"accounts"`
+}
+
+// LoadConfig reads and validates an account-watch YAML file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account-watch config %s: %w", path, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse account-watch config %s: %w", path, err)
+	}
+
+	for _, account := range config.Accounts {
+		if account.Name == "" || account.Pubkey == "" {
+			return nil, fmt.Errorf("account-watch config %s: entries must set both name and pubkey", path)
+		}
+		if _, err := NewDecoder(account.Decoder); err != nil {
+			return nil, fmt.Errorf("account-watch config %s: account %s: %w", path, account.Name, err)
+		}
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,124 @@
+package accountwatch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Fields is the set of named, numeric values a Decoder extracts from one account's data. Each
+// key becomes the "field" label on the solana_account_field gauge.
+type Fields map[string]float64
+
+// Decoder turns an account's raw lamports balance and data into a set of named fields.
+type Decoder interface {
+	Decode(lamports uint64, data []byte) (Fields, error)
+}
+
+// Solana's vote and stake program account layouts used by the simplified built-in decoders
+// below. These are fixed-offset approximations of the real (bincode-serialized) account
+// layouts, sufficient for tracking the handful of fields operators care about without pulling
+// in a full borsh/bincode decoder.
+const (
+	voteCreditsOffset      = 4 + 32 + 32 + 1 + 4       // version tag, node_pubkey, authorized withdrawer, commission, prior voters len
+	stakeDelegationOffset  = 4 + 8 + 8 + 32            // stake account rent-exempt reserve + meta prefix
+	minVoteCreditsDataSize = voteCreditsOffset + 8
+	minStakeDataSize       = stakeDelegationOffset + 8
+)
+
+// NewDecoder parses a decoder spec such as "u64_le@40", "lamports", "vote_account_credits", or
+// "raw_bytes_hash" into a Decoder.
+func NewDecoder(spec string) (Decoder, error) {
+	switch {
+	case spec == "lamports":
+		return lamportsDecoder{}, nil
+	case spec == "vote_account_credits":
+		return voteAccountCreditsDecoder{}, nil
+	case spec == "stake_delegation":
+		return stakeDelegationDecoder{}, nil
+	case spec == "raw_bytes_hash" || spec == "":
+		return rawBytesHashDecoder{}, nil
+	case strings.HasPrefix(spec, "u64_le@"):
+		offset, err := parseOffset(spec, "u64_le@")
+		if err != nil {
+			return nil, err
+		}
+		return fixedWidthDecoder{offset: offset, width: 8, signed: false}, nil
+	case strings.HasPrefix(spec, "i64_le@"):
+		offset, err := parseOffset(spec, "i64_le@")
+		if err != nil {
+			return nil, err
+		}
+		return fixedWidthDecoder{offset: offset, width: 8, signed: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown decoder %q", spec)
+	}
+}
+
+func parseOffset(spec, prefix string) (int, error) {
+	offset, err := strconv.Atoi(strings.TrimPrefix(spec, prefix))
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset in decoder %q: %w", spec, err)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid offset in decoder %q: must be non-negative", spec)
+	}
+	return offset, nil
+}
+
+type lamportsDecoder struct{}
+
+func (lamportsDecoder) Decode(lamports uint64, _ []byte) (Fields, error) {
+	return Fields{"lamports": float64(lamports)}, nil
+}
+
+type fixedWidthDecoder struct {
+	offset int
+	width  int
+	signed bool
+}
+
+func (d fixedWidthDecoder) Decode(_ uint64, data []byte) (Fields, error) {
+	if d.offset+d.width > len(data) {
+		return nil, fmt.Errorf("account data too short: need %d bytes at offset %d, have %d", d.width, d.offset, len(data))
+	}
+	raw := binary.LittleEndian.Uint64(data[d.offset : d.offset+d.width])
+	if d.signed {
+		return Fields{"value": float64(int64(raw))}, nil
+	}
+	return Fields{"value": float64(raw)}, nil
+}
+
+// voteAccountCreditsDecoder reports the vote account's most recently credited epoch total.
+type voteAccountCreditsDecoder struct{}
+
+func (voteAccountCreditsDecoder) Decode(_ uint64, data []byte) (Fields, error) {
+	if len(data) < minVoteCreditsDataSize {
+		return nil, fmt.Errorf("vote account data too short: have %d bytes", len(data))
+	}
+	credits := binary.LittleEndian.Uint64(data[voteCreditsOffset : voteCreditsOffset+8])
+	return Fields{"credits": float64(credits)}, nil
+}
+
+// stakeDelegationDecoder reports the currently delegated stake amount, in lamports.
+type stakeDelegationDecoder struct{}
+
+func (stakeDelegationDecoder) Decode(_ uint64, data []byte) (Fields, error) {
+	if len(data) < minStakeDataSize {
+		return nil, fmt.Errorf("stake account data too short: have %d bytes", len(data))
+	}
+	stake := binary.LittleEndian.Uint64(data[stakeDelegationOffset : stakeDelegationOffset+8])
+	return Fields{"delegated_stake": float64(stake)}, nil
+}
+
+// rawBytesHashDecoder is the fallback decoder for accounts with no structured layout support:
+// it reports a stable hash of the account data so operators can at least alert on changes.
+type rawBytesHashDecoder struct{}
+
+func (rawBytesHashDecoder) Decode(_ uint64, data []byte) (Fields, error) {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return Fields{"hash": float64(h.Sum64())}, nil
+}
@@ -0,0 +1,129 @@
+package accountwatch
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// Real captured vote/stake account bytes from a live cluster would pin these decoders against
+// the actual bincode layout; lacking that, these synthetic buffers instead pin the documented
+// offset arithmetic itself (voteCreditsOffset, stakeDelegationOffset) so a future edit that
+// shifts one of those constants is caught here rather than silently changing what
+// solana_account_field reports.
+func TestNewDecoder(t *testing.T) {
+	cases := []struct {
+		spec    string
+		want    Decoder
+		wantErr bool
+	}{
+		{spec: "lamports", want: lamportsDecoder{}},
+		{spec: "vote_account_credits", want: voteAccountCreditsDecoder{}},
+		{spec: "stake_delegation", want: stakeDelegationDecoder{}},
+		{spec: "raw_bytes_hash", want: rawBytesHashDecoder{}},
+		{spec: "", want: rawBytesHashDecoder{}},
+		{spec: "u64_le@40", want: fixedWidthDecoder{offset: 40, width: 8, signed: false}},
+		{spec: "i64_le@16", want: fixedWidthDecoder{offset: 16, width: 8, signed: true}},
+		{spec: "u64_le@-1", wantErr: true},
+		{spec: "u64_le@nope", wantErr: true},
+		{spec: "unknown_spec", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := NewDecoder(tc.spec)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("NewDecoder(%q): expected an error, got none", tc.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewDecoder(%q): unexpected error: %v", tc.spec, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("NewDecoder(%q) = %#v, want %#v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestLamportsDecoder(t *testing.T) {
+	fields, err := lamportsDecoder{}.Decode(12345, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["lamports"] != 12345 {
+		t.Errorf("lamports = %v, want 12345", fields["lamports"])
+	}
+}
+
+func TestFixedWidthDecoder(t *testing.T) {
+	data := make([]byte, 24)
+	binary.LittleEndian.PutUint64(data[8:16], 42)
+
+	fields, err := fixedWidthDecoder{offset: 8, width: 8, signed: false}.Decode(0, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["value"] != 42 {
+		t.Errorf("value = %v, want 42", fields["value"])
+	}
+
+	if _, err := (fixedWidthDecoder{offset: 20, width: 8, signed: false}).Decode(0, data); err == nil {
+		t.Error("expected an error when offset+width exceeds the buffer length")
+	}
+}
+
+func TestVoteAccountCreditsDecoder(t *testing.T) {
+	data := make([]byte, minVoteCreditsDataSize)
+	binary.LittleEndian.PutUint64(data[73:81], 987654) // voteCreditsOffset = 4+32+32+1+4
+
+	fields, err := voteAccountCreditsDecoder{}.Decode(0, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["credits"] != 987654 {
+		t.Errorf("credits = %v, want 987654", fields["credits"])
+	}
+
+	if _, err := voteAccountCreditsDecoder{}.Decode(0, data[:minVoteCreditsDataSize-1]); err == nil {
+		t.Error("expected an error when the vote account data is too short")
+	}
+}
+
+func TestStakeDelegationDecoder(t *testing.T) {
+	data := make([]byte, minStakeDataSize)
+	binary.LittleEndian.PutUint64(data[52:60], 5_000_000_000) // stakeDelegationOffset = 4+8+8+32
+
+	fields, err := stakeDelegationDecoder{}.Decode(0, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["delegated_stake"] != 5_000_000_000 {
+		t.Errorf("delegated_stake = %v, want 5000000000", fields["delegated_stake"])
+	}
+
+	if _, err := stakeDelegationDecoder{}.Decode(0, data[:minStakeDataSize-1]); err == nil {
+		t.Error("expected an error when the stake account data is too short")
+	}
+}
+
+func TestRawBytesHashDecoder_IsStableAndSensitiveToContent(t *testing.T) {
+	a, err := rawBytesHashDecoder{}.Decode(0, []byte("account-a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	aAgain, err := rawBytesHashDecoder{}.Decode(0, []byte("account-a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a["hash"] != aAgain["hash"] {
+		t.Error("expected the same input bytes to hash to the same value")
+	}
+
+	b, err := rawBytesHashDecoder{}.Decode(0, []byte("account-b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a["hash"] == b["hash"] {
+		t.Error("expected different input bytes to hash to different values")
+	}
+}
@@ -0,0 +1,138 @@
+// Package accountwatch polls a configured set of program-owned accounts and publishes their
+// decoded fields as Prometheus metrics, similarly to how the Wormhole Solana watcher decodes
+// program-owned account state.
+package accountwatch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/rpc"
+	"github.com/asymmetric-research/solana_exporter/pkg/slog"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	NameLabel       = "name"
+	FieldLabel      = "field"
+	CommitmentLabel = "commitment"
+)
+
+type watchedAccount struct {
+	AccountConfig
+	decoder Decoder
+}
+
+// Watcher periodically fetches a configured list of accounts via getMultipleAccounts and
+// publishes their decoded fields and last-observed slot as gauges.
+type Watcher struct {
+	provider   rpc.Provider
+	commitment rpc.Commitment
+	pollPeriod time.Duration
+	accounts   []watchedAccount
+	logger     *zap.SugaredLogger
+
+	AccountField    *prometheus.GaugeVec
+	AccountLastSlot *prometheus.GaugeVec
+}
+
+// NewWatcher builds a Watcher from a loaded Config. It returns an error if any account's
+// decoder spec fails to parse.
+func NewWatcher(provider rpc.Provider, commitment rpc.Commitment, pollPeriod time.Duration, config *Config) (*Watcher, error) {
+	accounts := make([]watchedAccount, 0, len(config.Accounts))
+	for _, account := range config.Accounts {
+		decoder, err := NewDecoder(account.Decoder)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", account.Name, err)
+		}
+		accounts = append(accounts, watchedAccount{AccountConfig: account, decoder: decoder})
+	}
+
+	return &Watcher{
+		provider:   provider,
+		commitment: commitment,
+		pollPeriod: pollPeriod,
+		accounts:   accounts,
+		logger:     slog.Get(),
+		AccountField: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "solana_account_field",
+				Help: "Decoded field values of configured program accounts, by account name and field.",
+			},
+			[]string{NameLabel, FieldLabel, CommitmentLabel},
+		),
+		AccountLastSlot: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "solana_account_last_update_slot",
+				Help: "The slot at which a configured account was last successfully polled, by account name.",
+			},
+			[]string{NameLabel},
+		),
+	}, nil
+}
+
+// Run polls all configured accounts every pollPeriod until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollPeriod)
+	defer ticker.Stop()
+
+	w.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	if len(w.accounts) == 0 {
+		return
+	}
+
+	pubkeys := make([]string, len(w.accounts))
+	for i, account := range w.accounts {
+		pubkeys[i] = account.Pubkey
+	}
+
+	infos, err := w.provider.GetMultipleAccounts(ctx, w.commitment, pubkeys)
+	if err != nil {
+		w.logger.Errorf("failed to poll watched accounts: %v", err)
+		return
+	}
+
+	byPubkey := make(map[string]rpc.AccountInfo, len(infos))
+	for _, info := range infos {
+		byPubkey[info.Pubkey] = info
+	}
+
+	slot, slotErr := w.provider.GetSlot(ctx, w.commitment)
+	if slotErr != nil {
+		w.logger.Warnf("failed to get slot while polling watched accounts: %v", slotErr)
+	}
+
+	for _, account := range w.accounts {
+		info, ok := byPubkey[account.Pubkey]
+		if !ok {
+			w.logger.Warnf("account %s (%s) not found", account.Name, account.Pubkey)
+			continue
+		}
+
+		fields, err := account.decoder.Decode(info.Lamports, info.Data)
+		if err != nil {
+			w.logger.Errorf("failed to decode account %s (%s): %v", account.Name, account.Pubkey, err)
+			continue
+		}
+
+		for field, value := range fields {
+			w.AccountField.WithLabelValues(account.Name, field, string(w.commitment)).Set(value)
+		}
+		if slotErr == nil {
+			w.AccountLastSlot.WithLabelValues(account.Name).Set(float64(slot))
+		}
+	}
+}
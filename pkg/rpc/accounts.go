@@ -0,0 +1,11 @@
+package rpc
+
+// AccountInfo is the decoded representation of a single Solana account, as returned by
+// getMultipleAccounts / getAccountInfo.
+type AccountInfo struct {
+	Pubkey     string
+	Owner      string
+	Lamports   uint64
+	Data       []byte
+	Executable bool
+}
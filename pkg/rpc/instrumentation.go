@@ -0,0 +1,139 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	rpcMethodLabel     = "method"
+	rpcCommitmentLabel = "commitment"
+	rpcReasonLabel     = "reason"
+
+	reasonTimeout     = "timeout"
+	reasonHttpError   = "http_error"
+	reasonParseError  = "parse_error"
+	reasonRpcErrorFmt = "rpc_error_%d"
+)
+
+// InstrumentedProvider wraps a Provider and records request latency and typed errors for every
+// call, following the pattern used by the Wormhole watchers
+// (wormhole_eth_query_latency, wormhole_solana_connection_errors_total).
+type InstrumentedProvider struct {
+	Provider
+
+	RequestDuration *prometheus.HistogramVec
+	Errors          *prometheus.CounterVec
+}
+
+// NewInstrumentedProvider wraps next, instrumenting the subset of Provider methods that the
+// exporter calls directly. Calls not explicitly overridden below pass through uninstrumented.
+func NewInstrumentedProvider(next Provider) *InstrumentedProvider {
+	return &InstrumentedProvider{
+		Provider: next,
+		RequestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "solana_rpc_request_duration_seconds",
+				Help:    "Latency of Solana RPC requests, by method and commitment.",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{rpcMethodLabel, rpcCommitmentLabel},
+		),
+		Errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "solana_rpc_errors_total",
+				Help: "Total number of Solana RPC errors, by method, commitment and reason.",
+			},
+			[]string{rpcMethodLabel, rpcCommitmentLabel, rpcReasonLabel},
+		),
+	}
+}
+
+// errorReason classifies err into the coarse buckets used by the reason label: a JSON-RPC error
+// code, a context timeout, an HTTP-level error, or an unparseable response.
+func errorReason(err error) string {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return fmt.Sprintf(reasonRpcErrorFmt, rpcErr.Code)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return reasonTimeout
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return reasonParseError
+	}
+	return reasonHttpError
+}
+
+func (p *InstrumentedProvider) observe(method string, commitment Commitment, start time.Time, err error) {
+	p.RequestDuration.WithLabelValues(method, string(commitment)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		p.Errors.WithLabelValues(method, string(commitment), errorReason(err)).Inc()
+	}
+}
+
+func (p *InstrumentedProvider) GetVoteAccounts(
+	ctx context.Context, commitment Commitment, votePubkey *string,
+) (*VoteAccounts, error) {
+	start := time.Now()
+	result, err := p.Provider.GetVoteAccounts(ctx, commitment, votePubkey)
+	p.observe("getVoteAccounts", commitment, start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetVersion(ctx context.Context) (string, error) {
+	start := time.Now()
+	result, err := p.Provider.GetVersion(ctx)
+	p.observe("getVersion", "", start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetHealth(ctx context.Context) (string, error) {
+	start := time.Now()
+	result, err := p.Provider.GetHealth(ctx)
+	p.observe("getHealth", "", start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetMinimumLedgerSlot(ctx context.Context) (*int64, error) {
+	start := time.Now()
+	result, err := p.Provider.GetMinimumLedgerSlot(ctx)
+	p.observe("minimumLedgerSlot", "", start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetFirstAvailableBlock(ctx context.Context) (*int64, error) {
+	start := time.Now()
+	result, err := p.Provider.GetFirstAvailableBlock(ctx)
+	p.observe("getFirstAvailableBlock", "", start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetMultipleAccounts(
+	ctx context.Context, commitment Commitment, pubkeys []string,
+) ([]AccountInfo, error) {
+	start := time.Now()
+	result, err := p.Provider.GetMultipleAccounts(ctx, commitment, pubkeys)
+	p.observe("getMultipleAccounts", commitment, start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetSlot(ctx context.Context, commitment Commitment) (int64, error) {
+	start := time.Now()
+	result, err := p.Provider.GetSlot(ctx, commitment)
+	p.observe("getSlot", commitment, start, err)
+	return result, err
+}
+
+func (p *InstrumentedProvider) GetIdentity(ctx context.Context) (string, error) {
+	start := time.Now()
+	result, err := p.Provider.GetIdentity(ctx)
+	p.observe("getIdentity", "", start, err)
+	return result, err
+}
@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/slog"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// FailoverPolicy selects how MultiClient spreads requests across its configured endpoints.
+type FailoverPolicy string
+
+const (
+	FailoverSequential FailoverPolicy = "sequential"
+	FailoverRoundRobin FailoverPolicy = "round-robin"
+	FailoverShadow     FailoverPolicy = "shadow"
+)
+
+type multiClientEndpoint struct {
+	url      string
+	provider Provider
+}
+
+// MultiClient is a Provider backed by an ordered list of RPC endpoints. Depending on the
+// configured FailoverPolicy it either fails a call over to the next endpoint, load-balances
+// round-robin, or shadows the call to every endpoint. Every Provider method the exporter calls
+// is dispatched through dispatch(), so failover applies uniformly rather than to a hand-picked
+// subset of methods; the one exception is GetSlot under FailoverShadow, which shadows the call
+// itself and is the only method whose results are compared across endpoints.
+type MultiClient struct {
+	endpoints []multiClientEndpoint
+	policy    FailoverPolicy
+	tolerance int64
+	next      atomic.Uint64
+	logger    *zap.SugaredLogger
+
+	Up           *prometheus.GaugeVec
+	Disagreement *prometheus.CounterVec
+}
+
+// NewMultiClient builds a MultiClient over urls using the given failover policy. tolerance is
+// the maximum slot difference endpoints may disagree on before it is reported as a
+// disagreement (only meaningful for FailoverShadow).
+func NewMultiClient(urls []string, timeout time.Duration, policy FailoverPolicy, tolerance int64) *MultiClient {
+	endpoints := make([]multiClientEndpoint, len(urls))
+	for i, url := range urls {
+		endpoints[i] = multiClientEndpoint{url: url, provider: NewRPCClient(url, timeout)}
+	}
+
+	return &MultiClient{
+		endpoints: endpoints,
+		policy:    policy,
+		tolerance: tolerance,
+		logger:    slog.Get(),
+		Up: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "solana_rpc_endpoint_up",
+				Help: "Whether a specific RPC endpoint (url) is currently reachable.",
+			},
+			[]string{"url"},
+		),
+		Disagreement: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "solana_rpc_endpoint_disagreement_total",
+				Help: "Total number of times shadowed RPC endpoints disagreed on a result beyond tolerance, " +
+					"by method and the disagreeing endpoints.",
+			},
+			[]string{"method", "endpoints"},
+		),
+	}
+}
+
+func (c *MultiClient) markUp(url string, err error) {
+	if err != nil {
+		c.Up.WithLabelValues(url).Set(0)
+		return
+	}
+	c.Up.WithLabelValues(url).Set(1)
+}
+
+// dispatch runs call against c's endpoints according to the configured FailoverPolicy:
+// sequential tries each endpoint in order until one succeeds, round-robin picks a single
+// endpoint in rotation, and shadow calls every endpoint in parallel (for Up visibility) but only
+// returns the primary (first-configured) endpoint's result.
+func dispatch[T any](c *MultiClient, ctx context.Context, call func(context.Context, Provider) (T, error)) (T, error) {
+	var zero T
+	if len(c.endpoints) == 0 {
+		return zero, fmt.Errorf("no RPC endpoints configured")
+	}
+
+	switch c.policy {
+	case FailoverRoundRobin:
+		endpoint := c.endpoints[c.next.Add(1)%uint64(len(c.endpoints))]
+		result, err := call(ctx, endpoint.provider)
+		c.markUp(endpoint.url, err)
+		return result, err
+	case FailoverShadow:
+		results := make([]T, len(c.endpoints))
+		errs := make([]error, len(c.endpoints))
+		var wg sync.WaitGroup
+		for i, endpoint := range c.endpoints {
+			i, endpoint := i, endpoint
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := call(ctx, endpoint.provider)
+				c.markUp(endpoint.url, err)
+				results[i], errs[i] = result, err
+			}()
+		}
+		wg.Wait()
+		return results[0], errs[0]
+	default: // FailoverSequential
+		var lastErr error
+		for _, endpoint := range c.endpoints {
+			result, err := call(ctx, endpoint.provider)
+			c.markUp(endpoint.url, err)
+			if err == nil {
+				return result, nil
+			}
+			lastErr = err
+		}
+		return zero, lastErr
+	}
+}
+
+func (c *MultiClient) GetHealth(ctx context.Context) (string, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) (string, error) {
+		return p.GetHealth(ctx)
+	})
+}
+
+func (c *MultiClient) GetVersion(ctx context.Context) (string, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) (string, error) {
+		return p.GetVersion(ctx)
+	})
+}
+
+func (c *MultiClient) GetIdentity(ctx context.Context) (string, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) (string, error) {
+		return p.GetIdentity(ctx)
+	})
+}
+
+func (c *MultiClient) GetMinimumLedgerSlot(ctx context.Context) (*int64, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) (*int64, error) {
+		return p.GetMinimumLedgerSlot(ctx)
+	})
+}
+
+func (c *MultiClient) GetFirstAvailableBlock(ctx context.Context) (*int64, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) (*int64, error) {
+		return p.GetFirstAvailableBlock(ctx)
+	})
+}
+
+func (c *MultiClient) GetVoteAccounts(
+	ctx context.Context, commitment Commitment, votePubkey *string,
+) (*VoteAccounts, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) (*VoteAccounts, error) {
+		return p.GetVoteAccounts(ctx, commitment, votePubkey)
+	})
+}
+
+func (c *MultiClient) GetMultipleAccounts(
+	ctx context.Context, commitment Commitment, pubkeys []string,
+) ([]AccountInfo, error) {
+	return dispatch(c, ctx, func(ctx context.Context, p Provider) ([]AccountInfo, error) {
+		return p.GetMultipleAccounts(ctx, commitment, pubkeys)
+	})
+}
+
+// GetSlot is dispatched like any other Provider method, except under FailoverShadow it also
+// compares every endpoint's reported slot against the primary endpoint's and emits a
+// disagreement metric when they differ by more than c.tolerance.
+func (c *MultiClient) GetSlot(ctx context.Context, commitment Commitment) (int64, error) {
+	if c.policy != FailoverShadow || len(c.endpoints) < 2 {
+		return dispatch(c, ctx, func(ctx context.Context, p Provider) (int64, error) {
+			return p.GetSlot(ctx, commitment)
+		})
+	}
+
+	type observation struct {
+		url  string
+		slot int64
+		err  error
+	}
+	observations := make([]observation, len(c.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range c.endpoints {
+		i, endpoint := i, endpoint
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slot, err := endpoint.provider.GetSlot(ctx, commitment)
+			c.markUp(endpoint.url, err)
+			observations[i] = observation{url: endpoint.url, slot: slot, err: err}
+		}()
+	}
+	wg.Wait()
+
+	primary := observations[0]
+	var disagreeing []string
+	for _, obs := range observations[1:] {
+		if obs.err != nil || primary.err != nil {
+			continue
+		}
+		if abs(obs.slot-primary.slot) > c.tolerance {
+			disagreeing = append(disagreeing, obs.url)
+		}
+	}
+	if len(disagreeing) > 0 {
+		c.Disagreement.WithLabelValues("getSlot", strings.Join(disagreeing, ",")).Inc()
+	}
+	if primary.err != nil {
+		return 0, fmt.Errorf("getSlot failed on primary endpoint %s: %w", c.endpoints[0].url, primary.err)
+	}
+	return primary.slot, nil
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
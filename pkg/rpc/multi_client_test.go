@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeProvider is a Provider whose GetSlot (the only method exercised below) returns a
+// configured slot/error, optionally blocking until released so tests can assert on
+// parallelism. All other methods are unused by these tests and panic if called.
+type fakeProvider struct {
+	slot    int64
+	err     error
+	delay   time.Duration
+	calls   *atomic.Int64
+	release chan struct{}
+}
+
+func (f *fakeProvider) GetSlot(ctx context.Context, commitment Commitment) (int64, error) {
+	if f.calls != nil {
+		f.calls.Add(1)
+	}
+	if f.release != nil {
+		<-f.release
+	} else if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	return f.slot, f.err
+}
+
+func (f *fakeProvider) GetHealth(ctx context.Context) (string, error) { panic("not used") }
+func (f *fakeProvider) GetVersion(ctx context.Context) (string, error) { panic("not used") }
+func (f *fakeProvider) GetIdentity(ctx context.Context) (string, error) { panic("not used") }
+func (f *fakeProvider) GetMinimumLedgerSlot(ctx context.Context) (*int64, error) { panic("not used") }
+func (f *fakeProvider) GetFirstAvailableBlock(ctx context.Context) (*int64, error) {
+	panic("not used")
+}
+func (f *fakeProvider) GetVoteAccounts(
+	ctx context.Context, commitment Commitment, votePubkey *string,
+) (*VoteAccounts, error) {
+	panic("not used")
+}
+func (f *fakeProvider) GetMultipleAccounts(
+	ctx context.Context, commitment Commitment, pubkeys []string,
+) ([]AccountInfo, error) {
+	panic("not used")
+}
+
+func newTestMultiClient(policy FailoverPolicy, tolerance int64, providers ...Provider) *MultiClient {
+	c := NewMultiClient(nil, time.Second, policy, tolerance)
+	c.endpoints = make([]multiClientEndpoint, len(providers))
+	for i, p := range providers {
+		c.endpoints[i] = multiClientEndpoint{url: fmt.Sprintf("endpoint-%d", i), provider: p}
+	}
+	return c
+}
+
+func TestMultiClientGetSlot_SequentialFailsOverToNextEndpoint(t *testing.T) {
+	primary := &fakeProvider{err: fmt.Errorf("connection refused")}
+	backup := &fakeProvider{slot: 100}
+	c := newTestMultiClient(FailoverSequential, 0, primary, backup)
+
+	slot, err := c.GetSlot(context.Background(), Commitment("confirmed"))
+	if err != nil {
+		t.Fatalf("expected backup endpoint to serve the request, got err: %v", err)
+	}
+	if slot != 100 {
+		t.Fatalf("expected slot 100 from backup endpoint, got %d", slot)
+	}
+}
+
+func TestMultiClientGetSlot_ShadowPropagatesPrimaryError(t *testing.T) {
+	primary := &fakeProvider{err: fmt.Errorf("primary down")}
+	secondary := &fakeProvider{slot: 100}
+	c := newTestMultiClient(FailoverShadow, 10, primary, secondary)
+
+	_, err := c.GetSlot(context.Background(), Commitment("confirmed"))
+	if err == nil {
+		t.Fatal("expected a failed primary endpoint to produce an error, got nil")
+	}
+}
+
+func TestMultiClientGetSlot_ShadowReportsDisagreementBeyondTolerance(t *testing.T) {
+	primary := &fakeProvider{slot: 100}
+	secondary := &fakeProvider{slot: 200}
+	c := newTestMultiClient(FailoverShadow, 10, primary, secondary)
+
+	slot, err := c.GetSlot(context.Background(), Commitment("confirmed"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slot != 100 {
+		t.Fatalf("expected the primary endpoint's slot to be returned, got %d", slot)
+	}
+
+	if count := testutil.ToFloat64(c.Disagreement.WithLabelValues("getSlot", "endpoint-1")); count != 1 {
+		t.Fatalf("expected exactly one disagreement to be recorded, got %v", count)
+	}
+}
+
+func TestMultiClientGetSlot_ShadowRunsEndpointsConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	var inFlight atomic.Int64
+	blocking := func() *fakeProvider {
+		return &fakeProvider{release: release, calls: &inFlight}
+	}
+	c := newTestMultiClient(FailoverShadow, 0, blocking(), blocking(), blocking())
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = c.GetSlot(context.Background(), Commitment("confirmed"))
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for inFlight.Load() != 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := inFlight.Load(); got != 3 {
+		t.Fatalf("expected all 3 shadowed endpoints to be called concurrently, only %d were in flight", got)
+	}
+	close(release)
+	<-done
+}
+
+func TestMultiClientDispatch_RoundRobinRotatesEndpoints(t *testing.T) {
+	first := &fakeProvider{slot: 0}
+	second := &fakeProvider{slot: 1}
+	c := newTestMultiClient(FailoverRoundRobin, 0, first, second)
+
+	seen := make(map[int64]int)
+	for i := 0; i < 4; i++ {
+		slot, err := c.GetSlot(context.Background(), Commitment("confirmed"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		seen[slot]++
+	}
+	if seen[0] == 0 || seen[1] == 0 {
+		t.Fatalf("expected round-robin to hit both endpoints, saw: %v", seen)
+	}
+}
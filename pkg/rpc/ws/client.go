@@ -0,0 +1,238 @@
+// Package ws provides a persistent WebSocket subscription to a Solana node, keeping an
+// in-memory cache of slot and vote state that is cheaper to read than issuing RPC calls.
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asymmetric-research/solana_exporter/pkg/slog"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Cache holds the latest values observed over the slotSubscribe, rootSubscribe and
+// voteSubscribe notifications. All fields are safe for concurrent access via the accessor
+// methods below.
+type Cache struct {
+	mu sync.RWMutex
+
+	currentSlot int64
+	rootSlot    int64
+	lastVote    map[string]int64 // votekey -> last voted-on slot
+}
+
+func newCache() *Cache {
+	return &Cache{
+		lastVote: make(map[string]int64),
+	}
+}
+
+func (c *Cache) CurrentSlot() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.currentSlot
+}
+
+func (c *Cache) RootSlot() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rootSlot
+}
+
+func (c *Cache) LastVote(votekey string) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	slot, ok := c.lastVote[votekey]
+	return slot, ok
+}
+
+// Client maintains a persistent WebSocket connection to a Solana node and keeps a Cache
+// up to date via slotSubscribe, rootSubscribe and voteSubscribe notifications. It reconnects
+// and re-subscribes automatically when the connection drops.
+type Client struct {
+	url    string
+	logger *zap.SugaredLogger
+
+	cache *Cache
+
+	connected  atomic.Bool
+	reconnects atomic.Uint64
+
+	// Notify receives a signal after every processed notification, so that consumers (e.g. the
+	// slot watcher) can react to updates immediately instead of polling the Cache on a timer.
+	// Sends are non-blocking: a slow or absent consumer just misses the odd signal, it never
+	// stalls notification processing.
+	Notify chan struct{}
+}
+
+// NewClient creates a Client for the given WebSocket URL. Call Run to start the
+// connect/subscribe loop; it blocks until ctx is cancelled.
+func NewClient(url string) *Client {
+	return &Client{
+		url:    url,
+		logger: slog.Get(),
+		cache:  newCache(),
+		Notify: make(chan struct{}, 1),
+	}
+}
+
+func (c *Client) Cache() *Cache { return c.cache }
+
+// Url returns the WebSocket URL this client connects to.
+func (c *Client) Url() string { return c.url }
+
+// Connected reports whether the WebSocket connection is currently established.
+func (c *Client) Connected() bool { return c.connected.Load() }
+
+// Reconnects returns the total number of times the connection has been (re-)established,
+// including the initial connection.
+func (c *Client) Reconnects() uint64 { return c.reconnects.Load() }
+
+const (
+	wsReconnectMinBackoff = 1 * time.Second
+	wsReconnectMaxBackoff = 30 * time.Second
+)
+
+// Run connects to the node and processes notifications until ctx is cancelled, reconnecting
+// with the underlying connection on any error. Reconnect attempts back off exponentially (capped
+// at wsReconnectMaxBackoff) while the connection keeps failing, and reset once a connection is
+// actually established, so a persistently unreachable node is retried patiently rather than
+// busy-looping.
+func (c *Client) Run(ctx context.Context) {
+	backoff := wsReconnectMinBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		connected, err := c.runOnce(ctx)
+		if err != nil {
+			c.logger.Warnf("ws connection to %s dropped: %v", c.url, err)
+		}
+		c.connected.Store(false)
+
+		if connected {
+			backoff = wsReconnectMinBackoff
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < wsReconnectMaxBackoff {
+			backoff *= 2
+			if backoff > wsReconnectMaxBackoff {
+				backoff = wsReconnectMaxBackoff
+			}
+		}
+	}
+}
+
+// runOnce dials, subscribes, and processes notifications until the connection drops or ctx is
+// cancelled. The returned bool reports whether the connection was ever established, so Run knows
+// whether to reset its reconnect backoff.
+func (c *Client) runOnce(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to dial %s: %w", c.url, err)
+	}
+	defer conn.Close()
+
+	c.reconnects.Add(1)
+	c.connected.Store(true)
+
+	for _, method := range []string{"slotSubscribe", "rootSubscribe", "voteSubscribe"} {
+		if err := conn.WriteJSON(subscribeRequest(method)); err != nil {
+			return true, fmt.Errorf("failed to send %s: %w", method, err)
+		}
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		c.handleNotification(data)
+	}
+}
+
+// signal performs a non-blocking send on Notify so a full channel (an unread prior signal)
+// never blocks notification processing.
+func (c *Client) signal() {
+	select {
+	case c.Notify <- struct{}{}:
+	default:
+	}
+}
+
+func subscribeRequest(method string) map[string]any {
+	return map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+	}
+}
+
+type notificationEnvelope struct {
+	Method string `json:"method"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+func (c *Client) handleNotification(data []byte) {
+	var envelope notificationEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		c.logger.Warnf("failed to unmarshal ws notification: %v", err)
+		return
+	}
+
+	defer c.signal()
+
+	switch envelope.Method {
+	case "slotNotification":
+		var slot struct {
+			Root int64 `json:"root"`
+			Slot int64 `json:"slot"`
+		}
+		if err := json.Unmarshal(envelope.Params.Result, &slot); err != nil {
+			c.logger.Warnf("failed to unmarshal slotNotification: %v", err)
+			return
+		}
+		c.cache.mu.Lock()
+		c.cache.currentSlot = slot.Slot
+		c.cache.mu.Unlock()
+	case "rootNotification":
+		var root int64
+		if err := json.Unmarshal(envelope.Params.Result, &root); err != nil {
+			c.logger.Warnf("failed to unmarshal rootNotification: %v", err)
+			return
+		}
+		c.cache.mu.Lock()
+		c.cache.rootSlot = root
+		c.cache.mu.Unlock()
+	case "voteNotification":
+		var vote struct {
+			VotePubkey string  `json:"votePubkey"`
+			Slots      []int64 `json:"slots"`
+		}
+		if err := json.Unmarshal(envelope.Params.Result, &vote); err != nil {
+			c.logger.Warnf("failed to unmarshal voteNotification: %v", err)
+			return
+		}
+		if len(vote.Slots) == 0 {
+			return
+		}
+		last := vote.Slots[len(vote.Slots)-1]
+		c.cache.mu.Lock()
+		c.cache.lastVote[vote.VotePubkey] = last
+		c.cache.mu.Unlock()
+	}
+}
@@ -0,0 +1,86 @@
+package ws
+
+import "testing"
+
+// newTestClient builds a Client without dialing anything, for exercising handleNotification and
+// the Cache accessors directly.
+func newTestClient() *Client {
+	return NewClient("wss://example.invalid")
+}
+
+func TestHandleNotification_Slot(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(`{"method":"slotNotification","params":{"result":{"root":10,"slot":12}}}`))
+
+	if got := c.Cache().CurrentSlot(); got != 12 {
+		t.Errorf("CurrentSlot() = %d, want 12", got)
+	}
+}
+
+func TestHandleNotification_Root(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(`{"method":"rootNotification","params":{"result":42}}`))
+
+	if got := c.Cache().RootSlot(); got != 42 {
+		t.Errorf("RootSlot() = %d, want 42", got)
+	}
+}
+
+func TestHandleNotification_Vote(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(
+		`{"method":"voteNotification","params":{"result":{"votePubkey":"abc","slots":[1,2,3]}}}`,
+	))
+
+	slot, ok := c.Cache().LastVote("abc")
+	if !ok {
+		t.Fatal("expected a last-vote entry for votekey \"abc\"")
+	}
+	if slot != 3 {
+		t.Errorf("LastVote(\"abc\") = %d, want 3 (the last element of slots)", slot)
+	}
+
+	if _, ok := c.Cache().LastVote("unknown"); ok {
+		t.Error("expected no last-vote entry for an unobserved votekey")
+	}
+}
+
+func TestHandleNotification_VoteIgnoresEmptySlots(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(
+		`{"method":"voteNotification","params":{"result":{"votePubkey":"abc","slots":[]}}}`,
+	))
+
+	if _, ok := c.Cache().LastVote("abc"); ok {
+		t.Error("expected an empty slots list to leave LastVote unset")
+	}
+}
+
+func TestHandleNotification_UnknownMethodIsIgnored(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(`{"method":"somethingElse","params":{"result":1}}`))
+
+	if got := c.Cache().CurrentSlot(); got != 0 {
+		t.Errorf("CurrentSlot() = %d, want 0 for an unrecognized notification method", got)
+	}
+}
+
+func TestHandleNotification_MalformedJSONIsIgnored(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(`not json`))
+
+	if got := c.Cache().CurrentSlot(); got != 0 {
+		t.Errorf("CurrentSlot() = %d, want 0 after a malformed notification", got)
+	}
+}
+
+func TestHandleNotification_Signals(t *testing.T) {
+	c := newTestClient()
+	c.handleNotification([]byte(`{"method":"rootNotification","params":{"result":1}}`))
+
+	select {
+	case <-c.Notify:
+	default:
+		t.Error("expected handleNotification to signal on Notify")
+	}
+}